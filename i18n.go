@@ -1,23 +1,15 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/fs"
 	"os"
 	"path/filepath"
-	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 )
 
-type TranslationKey struct {
-	Key       string
-	Namespace string
-	File      string
-}
-
 var i18nCmd = &cobra.Command{
 	Use:   "i18n",
 	Short: "Extract translation keys from React TSX files",
@@ -25,6 +17,27 @@ var i18nCmd = &cobra.Command{
 	Run:   runI18n,
 }
 
+var checkOnly bool
+var strictMode bool
+var i18nFormat string
+var flatOutput bool
+
+func init() {
+	i18nCmd.Flags().BoolVar(&checkOnly, "check", false, "Report drift without writing files; exit non-zero if any namespace has added or stale keys")
+	i18nCmd.Flags().BoolVar(&strictMode, "strict", false, "Exit non-zero if any key could not be statically resolved (dynamic keys, unresolved t bindings)")
+	i18nCmd.Flags().StringVar(&i18nFormat, "format", "json", "Output format: json, yaml, toml, or po")
+	i18nCmd.Flags().BoolVar(&flatOutput, "flat", false, "Write dot-separated keys as a flat map instead of nesting them (e.g. \"form.button.save\")")
+}
+
+// namespaceSummary reports how a namespace's translation file changed
+// relative to what was already on disk under .i18n/.
+type namespaceSummary struct {
+	Namespace string
+	Added     int
+	Unchanged int
+	Stale     int
+}
+
 func runI18n(cmd *cobra.Command, args []string) {
 	workDir, err := os.Getwd()
 	if err != nil {
@@ -32,247 +45,176 @@ func runI18n(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	keys, err := extractTranslationKeys(workDir)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error extracting translation keys: %v\n", err)
-		os.Exit(1)
-	}
-
-	err = setupI18nDirectory(workDir)
+	marshaller, err := marshallerFor(i18nFormat, !flatOutput)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error setting up .i18n directory: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	err = updateGitignore(workDir)
+	keys, warnings, err := extractTranslationKeys(workDir)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error updating .gitignore: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error extracting translation keys: %v\n", err)
 		os.Exit(1)
 	}
 
-	err = generateTranslationFiles(workDir, keys)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error generating translation files: %v\n", err)
-		os.Exit(1)
+	for _, warning := range warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
 	}
 
-	fmt.Printf("Successfully extracted %d translation keys and generated translation files\n", len(keys))
-}
-
-func extractTranslationKeys(rootDir string) ([]TranslationKey, error) {
-	// First pass: build global mapping of t-variables to namespaces
-	globalTranslationMap := make(map[string]string)
-
-	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+	if !checkOnly {
+		err = setupI18nDirectory(workDir)
 		if err != nil {
-			return err
+			fmt.Fprintf(os.Stderr, "Error setting up .i18n directory: %v\n", err)
+			os.Exit(1)
 		}
 
-		if d.IsDir() && shouldSkipDir(d.Name()) {
-			return filepath.SkipDir
-		}
-
-		if !strings.HasSuffix(path, ".tsx") {
-			return nil
-		}
-
-		fileMap, err := extractUseTranslationDeclarations(path)
+		err = updateGitignore(workDir)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Error parsing declarations in file %s: %v\n", path, err)
-			return nil
-		}
-
-		// Merge into global map
-		for tVar, namespace := range fileMap {
-			globalTranslationMap[tVar] = namespace
+			fmt.Fprintf(os.Stderr, "Error updating .gitignore: %v\n", err)
+			os.Exit(1)
 		}
+	}
 
-		return nil
-	})
-
+	summaries, err := generateTranslationFiles(workDir, keys, marshaller, checkOnly)
 	if err != nil {
-		return nil, err
+		fmt.Fprintf(os.Stderr, "Error generating translation files: %v\n", err)
+		os.Exit(1)
 	}
 
-	// Second pass: extract translation keys using the global mapping
-	var keys []TranslationKey
-
-	err = filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-
-		if d.IsDir() && shouldSkipDir(d.Name()) {
-			return filepath.SkipDir
-		}
-
-		if !strings.HasSuffix(path, ".tsx") {
-			return nil
-		}
-
-		fileKeys, err := parseFileWithMapping(path, globalTranslationMap)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Error parsing file %s: %v\n", path, err)
-			return nil
+	drift := false
+	for _, summary := range summaries {
+		fmt.Printf("%s: added=%d unchanged=%d stale=%d\n", summary.Namespace, summary.Added, summary.Unchanged, summary.Stale)
+		if summary.Added > 0 || summary.Stale > 0 {
+			drift = true
 		}
+	}
 
-		keys = append(keys, fileKeys...)
-		return nil
-	})
-
-	return keys, err
-}
+	fmt.Printf("Successfully extracted %d translation keys across %d namespace(s)\n", len(keys), len(summaries))
 
-func shouldSkipDir(dirName string) bool {
-	skipDirs := []string{"node_modules", "vendor", ".git", ".next", "dist", "build"}
-	for _, skip := range skipDirs {
-		if dirName == skip {
-			return true
-		}
+	if checkOnly && drift {
+		fmt.Fprintln(os.Stderr, "Translations are out of date; run `metadev i18n` to sync")
+		os.Exit(1)
 	}
-	return false
-}
 
-func parseFile(filePath string) ([]TranslationKey, error) {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
+	if strictMode && len(warnings) > 0 {
+		fmt.Fprintf(os.Stderr, "Strict mode: %d key(s) could not be statically resolved\n", len(warnings))
+		os.Exit(1)
 	}
+}
 
-	var keys []TranslationKey
-
-	useTranslationRegex := regexp.MustCompile(`const\s*{\s*t:\s*(\w+)\s*}\s*=\s*useTranslation\s*\(\s*['"]([^'"]+)['"]\s*\)`)
-	matches := useTranslationRegex.FindAllStringSubmatch(string(content), -1)
-
-	translationCallMap := make(map[string]string)
-	for _, match := range matches {
-		if len(match) >= 3 {
-			tVariable := match[1]
-			namespace := match[2]
-			translationCallMap[tVariable] = namespace
-		}
-	}
+// generateTranslationFiles merges newly discovered keys into each namespace's
+// existing .i18n/<namespace>.<ext> instead of overwriting it: existing
+// non-empty translations are preserved, newly discovered keys are added with
+// "", and keys that are no longer referenced are moved to a sibling
+// .i18n/<namespace>.stale.<ext> rather than deleted. When checkOnly is true,
+// nothing is written to disk and the function only computes drift.
+func generateTranslationFiles(rootDir string, keys []TranslationKey, marshaller Marshaller, checkOnly bool) ([]namespaceSummary, error) {
+	namespaceKeys := make(map[string][]TranslationKey)
 
-	for tVar, namespace := range translationCallMap {
-		callRegex := regexp.MustCompile(fmt.Sprintf(`%s\s*\(\s*['"]([^'"]+)['"]\s*\)`, regexp.QuoteMeta(tVar)))
-		callMatches := callRegex.FindAllStringSubmatch(string(content), -1)
-
-		for _, callMatch := range callMatches {
-			if len(callMatch) >= 2 {
-				key := callMatch[1]
-				keys = append(keys, TranslationKey{
-					Key:       key,
-					Namespace: namespace,
-					File:      filePath,
-				})
-			}
-		}
+	for _, key := range keys {
+		namespaceKeys[key.Namespace] = append(namespaceKeys[key.Namespace], key)
 	}
 
-	return keys, nil
-}
+	i18nDir := filepath.Join(rootDir, ".i18n")
+	ext := marshaller.Ext()
 
-func extractUseTranslationDeclarations(filePath string) (map[string]string, error) {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
+	namespaces := make([]string, 0, len(namespaceKeys))
+	for namespace := range namespaceKeys {
+		namespaces = append(namespaces, namespace)
 	}
+	sort.Strings(namespaces)
 
-	translationMap := make(map[string]string)
-	contentStr := string(content)
-
-	// Find all useTranslation declarations: const { t: tVariable } = useTranslation('namespace')
-	useTranslationRegex := regexp.MustCompile(`const\s*{\s*t:\s*(\w+)\s*}\s*=\s*useTranslation\s*\(\s*['"]([^'"]+)['"]\s*\)`)
-	matches := useTranslationRegex.FindAllStringSubmatch(contentStr, -1)
+	summaries := make([]namespaceSummary, 0, len(namespaces))
 
-	for _, match := range matches {
-		if len(match) >= 3 {
-			tVariable := match[1]
-			namespace := match[2]
-			translationMap[tVariable] = namespace
+	for _, namespace := range namespaces {
+		uniqueKeys := make(map[string]TranslationKey)
+		for _, key := range namespaceKeys[namespace] {
+			uniqueKeys[key.Key] = key
 		}
-	}
 
-	return translationMap, nil
-}
+		fileName := filepath.Join(i18nDir, fmt.Sprintf("%s.%s", namespace, ext))
+		existing, err := loadExistingTranslations(fileName, marshaller)
+		if err != nil {
+			return nil, fmt.Errorf("error loading existing translations for namespace %s: %v", namespace, err)
+		}
 
-func parseFileWithMapping(filePath string, globalMapping map[string]string) ([]TranslationKey, error) {
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
+		entries := make([]TranslationEntry, 0, len(uniqueKeys))
+		summary := namespaceSummary{Namespace: namespace}
 
-	var keys []TranslationKey
-	contentStr := string(content)
+		for key, translationKey := range uniqueKeys {
+			value := ""
+			if existingEntry, ok := existing[key]; ok {
+				value = existingEntry.Value
+				summary.Unchanged++
+			} else {
+				summary.Added++
+			}
+			entries = append(entries, TranslationEntry{Key: key, Value: value, Namespace: namespace, File: translationKey.File, Line: translationKey.Line, Plural: translationKey.Plural})
+		}
 
-	// Find all translation function calls (pattern: tSomething('key')) - must start with 't'
-	allCallsRegex := regexp.MustCompile(`\b(t[A-Za-z][A-Za-z0-9]*)\s*\(\s*['"]([^'"]+)['"]\s*\)`)
-	allCallMatches := allCallsRegex.FindAllStringSubmatch(contentStr, -1)
+		staleEntries := make([]TranslationEntry, 0)
+		for key, existingEntry := range existing {
+			if _, ok := uniqueKeys[key]; !ok {
+				staleEntries = append(staleEntries, TranslationEntry{Key: key, Value: existingEntry.Value, Namespace: namespace, Plural: existingEntry.Plural})
+			}
+		}
+		summary.Stale = len(staleEntries)
 
-	for _, callMatch := range allCallMatches {
-		if len(callMatch) >= 3 {
-			tVariable := callMatch[1]
-			key := callMatch[2]
+		if !checkOnly {
+			data, err := marshaller.Marshal(entries)
+			if err != nil {
+				return nil, fmt.Errorf("error marshaling %s for namespace %s: %v", ext, namespace, err)
+			}
+			if err := os.WriteFile(fileName, data, 0644); err != nil {
+				return nil, fmt.Errorf("error writing file %s: %v", fileName, err)
+			}
 
-			var namespace string
-			if ns, exists := globalMapping[tVariable]; exists {
-				// Use the namespace from global mapping
-				namespace = ns
-			} else {
-				// Infer namespace from variable name (e.g., tCommon -> common)
-				if strings.HasPrefix(tVariable, "t") && len(tVariable) > 1 {
-					namespace = strings.ToLower(tVariable[1:])
-				} else {
-					namespace = "common" // fallback to common
+			staleFileName := filepath.Join(i18nDir, fmt.Sprintf("%s.stale.%s", namespace, ext))
+			if len(staleEntries) > 0 {
+				staleData, err := marshaller.Marshal(staleEntries)
+				if err != nil {
+					return nil, fmt.Errorf("error marshaling stale %s for namespace %s: %v", ext, namespace, err)
+				}
+				if err := os.WriteFile(staleFileName, staleData, 0644); err != nil {
+					return nil, fmt.Errorf("error writing file %s: %v", staleFileName, err)
 				}
+			} else {
+				// No stale keys this run; remove any leftover file from a previous run.
+				os.Remove(staleFileName)
 			}
 
-			keys = append(keys, TranslationKey{
-				Key:       key,
-				Namespace: namespace,
-				File:      filePath,
-			})
+			fmt.Printf("Generated %s with %d keys (%d added, %d stale)\n", fileName, len(entries), summary.Added, summary.Stale)
 		}
+
+		summaries = append(summaries, summary)
 	}
 
-	return keys, nil
+	return summaries, nil
 }
 
-func generateTranslationFiles(rootDir string, keys []TranslationKey) error {
-	namespaceKeys := make(map[string][]string)
-
-	for _, key := range keys {
-		namespaceKeys[key.Namespace] = append(namespaceKeys[key.Namespace], key.Key)
+// loadExistingTranslations reads a namespace's translation file if it
+// already exists, returning an empty map when it does not. Keeping the full
+// TranslationEntry (rather than just the value) preserves Plural across
+// reruns, so a key's PO plural pairing survives even after it goes stale.
+func loadExistingTranslations(fileName string, marshaller Marshaller) (map[string]TranslationEntry, error) {
+	content, err := os.ReadFile(fileName)
+	if os.IsNotExist(err) {
+		return map[string]TranslationEntry{}, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	for namespace, keysList := range namespaceKeys {
-		translationMap := make(map[string]string)
-
-		uniqueKeys := make(map[string]bool)
-		for _, key := range keysList {
-			uniqueKeys[key] = true
-		}
-
-		for key := range uniqueKeys {
-			translationMap[key] = ""
-		}
-
-		jsonData, err := json.MarshalIndent(translationMap, "", "  ")
-		if err != nil {
-			return fmt.Errorf("error marshaling JSON for namespace %s: %v", namespace, err)
-		}
-
-		i18nDir := filepath.Join(rootDir, ".i18n")
-		fileName := filepath.Join(i18nDir, fmt.Sprintf("%s.json", namespace))
-		err = os.WriteFile(fileName, jsonData, 0644)
-		if err != nil {
-			return fmt.Errorf("error writing file %s: %v", fileName, err)
-		}
-
-		fmt.Printf("Generated %s with %d keys\n", fileName, len(translationMap))
+	entries, err := marshaller.Unmarshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse existing file %s: %v", fileName, err)
 	}
 
-	return nil
+	existing := make(map[string]TranslationEntry, len(entries))
+	for _, entry := range entries {
+		existing[entry.Key] = entry
+	}
+	return existing, nil
 }
 
 func setupI18nDirectory(rootDir string) error {
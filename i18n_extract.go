@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// TranslationKey is one translation entry discovered in a .tsx file.
+type TranslationKey struct {
+	Key       string
+	Namespace string
+	File      string
+	Line      int  // line of the call/element the key was extracted from
+	Plural    bool // t(key, { count }) - also implies <key>_one/<key>_other
+	Dynamic   bool // key couldn't be resolved to a literal string at scan time
+}
+
+// tBinding is what a `t`-like variable resolves to within a single file: the
+// namespace(s) passed to its useTranslation() call, in declaration order,
+// and an optional keyPrefix carried on the options object.
+type tBinding struct {
+	Namespaces []string
+	KeyPrefix  string
+}
+
+func (b tBinding) primaryNamespace() string {
+	if len(b.Namespaces) == 0 {
+		return "common"
+	}
+	return b.Namespaces[0]
+}
+
+// extractTranslationKeys walks every .tsx file under rootDir and extracts
+// its translation keys, returning any keys that could not be statically
+// resolved - dynamic concatenations/interpolations, or calls through a t
+// binding the file never declared - as warnings rather than failing.
+func extractTranslationKeys(rootDir string) ([]TranslationKey, []string, error) {
+	var keys []TranslationKey
+	var warnings []string
+
+	err := filepath.WalkDir(rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() && shouldSkipDir(d.Name()) {
+			return filepath.SkipDir
+		}
+
+		if !strings.HasSuffix(path, ".tsx") {
+			return nil
+		}
+
+		fileKeys, fileWarnings, err := extractKeysFromFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: Error parsing file %s: %v\n", path, err)
+			return nil
+		}
+
+		keys = append(keys, fileKeys...)
+		warnings = append(warnings, fileWarnings...)
+		return nil
+	})
+
+	return keys, warnings, err
+}
+
+func shouldSkipDir(dirName string) bool {
+	skipDirs := []string{"node_modules", "vendor", ".git", ".next", "dist", "build"}
+	for _, skip := range skipDirs {
+		if dirName == skip {
+			return true
+		}
+	}
+	return false
+}
+
+// extractKeysFromFile tokenizes a single .tsx file and resolves every t(...)
+// call and <Trans> element it contains into TranslationKey records.
+func extractKeysFromFile(path string) ([]TranslationKey, []string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contentStr := string(content)
+	events := tokenizeTSX(contentStr)
+	bindings := resolveTBindings(contentStr, events)
+
+	var keys []TranslationKey
+	var warnings []string
+
+	for _, event := range events {
+		switch event.Kind {
+		case eventCall:
+			if event.Name == "useTranslation" {
+				continue
+			}
+
+			binding, resolved := bindings[event.Name]
+			if !resolved {
+				if !looksLikeTranslationCall(event.Name) {
+					continue
+				}
+				binding = tBinding{Namespaces: []string{strings.ToLower(strings.TrimPrefix(event.Name, "t"))}}
+				warnings = append(warnings, fmt.Sprintf("%s:%d: %q is not a declared useTranslation binding; inferring namespace %q", path, event.Line, event.Name, binding.primaryNamespace()))
+			}
+
+			fileKeys, fileWarnings := translationKeysFromCall(event, binding, path)
+			keys = append(keys, fileKeys...)
+			warnings = append(warnings, fileWarnings...)
+
+		case eventJSXElement:
+			if event.Name != "Trans" {
+				continue
+			}
+			key, warning := translationKeyFromTrans(event, path)
+			if key != nil {
+				keys = append(keys, *key)
+			}
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+		}
+	}
+
+	return keys, warnings, nil
+}
+
+// resolveTBindings finds every `const { t } = useTranslation(...)` (and its
+// renamed/multi-namespace/keyPrefix variants) in a file and maps each bound
+// variable name to the namespace(s) it was declared with.
+func resolveTBindings(content string, events []tsxEvent) map[string]tBinding {
+	bindings := make(map[string]tBinding)
+
+	for _, event := range events {
+		if event.Kind != eventCall || event.Name != "useTranslation" {
+			continue
+		}
+
+		binding := tBinding{Namespaces: []string{"common"}}
+		if len(event.Args) > 0 {
+			if event.Args[0].IsLiteral {
+				binding.Namespaces = []string{event.Args[0].Literal}
+			} else if elems, ok := stringArrayLiteral(event.Args[0].Raw); ok {
+				binding.Namespaces = elems
+			}
+		}
+		if event.Options != "" {
+			binding.KeyPrefix = optionValue(event.Options, "keyPrefix")
+		}
+
+		for _, name := range boundNames(content, event.Offset) {
+			bindings[name] = binding
+		}
+	}
+
+	return bindings
+}
+
+// boundNames inspects the destructuring pattern immediately before a
+// useTranslation() call to find which variable(s) its `t` function was bound
+// to, e.g. "tAuth" in `const { t: tAuth } = useTranslation('auth')`. It scans
+// backward with brace-depth tracking rather than looking for the nearest
+// '{'/'}', since the enclosing function body's braces would otherwise be
+// mistaken for the destructuring pattern's.
+func boundNames(content string, callOffset int) []string {
+	i := skipSpaceBackward(content, callOffset)
+	if i == 0 || content[i-1] != '=' {
+		return nil
+	}
+	i = skipSpaceBackward(content, i-1)
+	if i == 0 || content[i-1] != '}' {
+		return nil
+	}
+	closeBrace := i - 1
+
+	depth := 0
+	openBrace := -1
+	for j := closeBrace; j >= 0; j-- {
+		switch content[j] {
+		case '}':
+			depth++
+		case '{':
+			depth--
+			if depth == 0 {
+				openBrace = j
+			}
+		}
+		if openBrace != -1 {
+			break
+		}
+	}
+	if openBrace == -1 {
+		return nil
+	}
+
+	var names []string
+	for _, part := range strings.Split(content[openBrace+1:closeBrace], ",") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "t":
+			names = append(names, "t")
+		case strings.HasPrefix(part, "t:"):
+			names = append(names, strings.TrimSpace(part[len("t:"):]))
+		}
+	}
+	return names
+}
+
+func skipSpaceBackward(content string, i int) int {
+	for i > 0 {
+		switch content[i-1] {
+		case ' ', '\t', '\n', '\r':
+			i--
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// looksLikeTranslationCall applies the repo's naming convention for
+// unresolved t bindings: a lowercase "t" followed by an uppercase letter,
+// e.g. tAuth, tCommon.
+func looksLikeTranslationCall(name string) bool {
+	return len(name) > 1 && name[0] == 't' && name[1] >= 'A' && name[1] <= 'Z'
+}
+
+// optionValue pulls a simple string property (e.g. keyPrefix: 'foo') out of
+// an options-object argument's raw source text.
+func optionValue(raw, key string) string {
+	idx := strings.Index(raw, key)
+	if idx == -1 {
+		return ""
+	}
+	rest := raw[idx+len(key):]
+	colon := strings.Index(rest, ":")
+	if colon == -1 {
+		return ""
+	}
+	rest = strings.TrimSpace(rest[colon+1:])
+	if len(rest) < 2 || (rest[0] != '\'' && rest[0] != '"') {
+		return ""
+	}
+	end := strings.IndexByte(rest[1:], rest[0])
+	if end == -1 {
+		return ""
+	}
+	return rest[1 : end+1]
+}
+
+// translationKeysFromCall resolves a single t(...) call into its
+// TranslationKey (or, for a pluralized call with a static key, into its
+// _one/_other variants in place of the bare key), splitting a leading "ns:"
+// prefix off the key into the namespace.
+func translationKeysFromCall(event tsxEvent, binding tBinding, path string) ([]TranslationKey, []string) {
+	if len(event.Args) == 0 {
+		return nil, nil
+	}
+
+	arg := event.Args[0]
+	namespace := binding.primaryNamespace()
+	keyStr := arg.Literal
+	dynamic := !arg.IsLiteral
+
+	if dynamic {
+		keyStr = arg.Raw
+	} else if ns, rest, ok := splitNamespacePrefix(keyStr); ok {
+		namespace = ns
+		keyStr = rest
+	}
+
+	if binding.KeyPrefix != "" && !dynamic {
+		keyStr = binding.KeyPrefix + "." + keyStr
+	}
+
+	var warnings []string
+	if dynamic {
+		warnings = append(warnings, fmt.Sprintf("%s:%d: dynamic key %q cannot be statically resolved", path, event.Line, keyStr))
+	}
+
+	// i18next never looks up the bare key when count is passed - only its
+	// _one/_other variants - so the plural case replaces the key entirely
+	// rather than adding to it.
+	if event.hasCountOption() && !dynamic {
+		return []TranslationKey{
+			{Key: keyStr + "_one", Namespace: namespace, File: path, Line: event.Line, Plural: true},
+			{Key: keyStr + "_other", Namespace: namespace, File: path, Line: event.Line, Plural: true},
+		}, warnings
+	}
+
+	key := TranslationKey{Key: keyStr, Namespace: namespace, File: path, Line: event.Line, Plural: event.hasCountOption(), Dynamic: dynamic}
+	return []TranslationKey{key}, warnings
+}
+
+// translationKeyFromTrans resolves a <Trans i18nKey="..." ns="..."> element.
+// values/components props carry no translation key and are ignored.
+func translationKeyFromTrans(event tsxEvent, path string) (*TranslationKey, string) {
+	i18nKey, ok := event.Attrs["i18nKey"]
+	if !ok || !i18nKey.IsLiteral {
+		return nil, fmt.Sprintf("%s:%d: <Trans> without a static i18nKey cannot be extracted", path, event.Line)
+	}
+
+	namespace := "common"
+	if ns, ok := event.Attrs["ns"]; ok && ns.IsLiteral {
+		namespace = ns.Literal
+	}
+
+	keyStr := i18nKey.Literal
+	if ns, rest, ok := splitNamespacePrefix(keyStr); ok {
+		namespace = ns
+		keyStr = rest
+	}
+
+	return &TranslationKey{Key: keyStr, Namespace: namespace, File: path, Line: event.Line}, ""
+}
+
+// splitNamespacePrefix splits i18next's "namespace:nested.key" syntax.
+func splitNamespacePrefix(key string) (namespace, rest string, ok bool) {
+	idx := strings.Index(key, ":")
+	if idx <= 0 {
+		return "", key, false
+	}
+	prefix := key[:idx]
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		if !isIdentPart(c) {
+			return "", key, false
+		}
+	}
+	return prefix, key[idx+1:], true
+}
@@ -0,0 +1,60 @@
+package main
+
+import "testing"
+
+func TestTranslationKeysFromCallPluralDropsBareKey(t *testing.T) {
+	events := tokenizeTSX(`t('items', { count })`)
+	binding := tBinding{Namespaces: []string{"common"}}
+
+	keys, warnings := translationKeysFromCall(events[0], binding, "Sample.tsx")
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	got := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		got[k.Key] = true
+	}
+
+	if got["items"] {
+		t.Fatalf("expected no bare %q key for a pluralized call, got keys %v", "items", keys)
+	}
+	if !got["items_one"] || !got["items_other"] {
+		t.Fatalf("expected items_one and items_other, got keys %v", keys)
+	}
+	if len(keys) != 2 {
+		t.Fatalf("expected exactly 2 keys, got %d: %v", len(keys), keys)
+	}
+}
+
+func TestTranslationKeysFromCallConcatenationIsDynamic(t *testing.T) {
+	events := tokenizeTSX(`t('a' + 'b')`)
+	binding := tBinding{Namespaces: []string{"common"}}
+
+	keys, warnings := translationKeysFromCall(events[0], binding, "Sample.tsx")
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 key, got %d: %v", len(keys), keys)
+	}
+	if !keys[0].Dynamic {
+		t.Fatalf("expected concatenated key to be flagged Dynamic, got %+v", keys[0])
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning for the dynamic key, got %v", warnings)
+	}
+}
+
+func TestTranslationKeysFromCallNamespacePrefixOverridesBinding(t *testing.T) {
+	events := tokenizeTSX(`tCommon('auth:login.title')`)
+	binding := tBinding{Namespaces: []string{"common"}}
+
+	keys, _ := translationKeysFromCall(events[0], binding, "Sample.tsx")
+	if len(keys) != 1 {
+		t.Fatalf("expected 1 key, got %d: %v", len(keys), keys)
+	}
+	if keys[0].Namespace != "auth" {
+		t.Fatalf("expected namespace %q from the ns:key prefix regardless of the tCommon binding, got %q", "auth", keys[0].Namespace)
+	}
+	if keys[0].Key != "login.title" {
+		t.Fatalf("expected key %q, got %q", "login.title", keys[0].Key)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateTranslationFilesAddedUnchangedStale(t *testing.T) {
+	dir := t.TempDir()
+	i18nDir := filepath.Join(dir, ".i18n")
+	if err := os.MkdirAll(i18nDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	writeJSONFile(t, filepath.Join(i18nDir, "common.json"), `{"greeting": "Hello", "gone": "Old"}`)
+
+	keys := []TranslationKey{
+		{Key: "greeting", Namespace: "common", File: "Sample.tsx", Line: 1},
+		{Key: "farewell", Namespace: "common", File: "Sample.tsx", Line: 2},
+	}
+
+	summaries, err := generateTranslationFiles(dir, keys, jsonMarshaller{nested: true}, false)
+	if err != nil {
+		t.Fatalf("generateTranslationFiles: %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected 1 namespace summary, got %d", len(summaries))
+	}
+
+	summary := summaries[0]
+	if summary.Unchanged != 1 || summary.Added != 1 || summary.Stale != 1 {
+		t.Fatalf("expected added=1 unchanged=1 stale=1, got %+v", summary)
+	}
+
+	got, err := readTranslationFile(filepath.Join(i18nDir, "common.json"))
+	if err != nil {
+		t.Fatalf("reading generated file: %v", err)
+	}
+	if got["greeting"] != "Hello" {
+		t.Fatalf("expected preserved existing translation, got %q", got["greeting"])
+	}
+	if _, ok := got["farewell"]; !ok {
+		t.Fatalf("expected newly discovered key to be added, got %#v", got)
+	}
+
+	stale, err := readTranslationFile(filepath.Join(i18nDir, "common.stale.json"))
+	if err != nil {
+		t.Fatalf("reading stale file: %v", err)
+	}
+	if stale["gone"] != "Old" {
+		t.Fatalf("expected stale key moved to sibling .stale. file, got %#v", stale)
+	}
+}
+
+// TestGenerateTranslationFilesCheckOnlyDoesNotWrite verifies that checkOnly
+// computes the same drift counts as a real run but leaves disk untouched.
+func TestGenerateTranslationFilesCheckOnlyDoesNotWrite(t *testing.T) {
+	dir := t.TempDir()
+	i18nDir := filepath.Join(dir, ".i18n")
+	if err := os.MkdirAll(i18nDir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	keys := []TranslationKey{
+		{Key: "greeting", Namespace: "common", File: "Sample.tsx", Line: 1},
+	}
+
+	summaries, err := generateTranslationFiles(dir, keys, jsonMarshaller{nested: true}, true)
+	if err != nil {
+		t.Fatalf("generateTranslationFiles: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Added != 1 {
+		t.Fatalf("expected added=1 in the computed summary, got %+v", summaries)
+	}
+
+	if _, err := os.Stat(filepath.Join(i18nDir, "common.json")); !os.IsNotExist(err) {
+		t.Fatalf("expected --check to leave no file on disk, stat err = %v", err)
+	}
+}
+
+func TestLoadExistingTranslationsPreservesPlural(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "common.po")
+	var m poMarshaller
+	data, err := m.Marshal([]TranslationEntry{
+		{Key: "items_one", Value: "one item", Plural: true},
+		{Key: "items_other", Value: "many items", Plural: true},
+	})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(file, data, 0644); err != nil {
+		t.Fatalf("writing %s: %v", file, err)
+	}
+
+	existing, err := loadExistingTranslations(file, m)
+	if err != nil {
+		t.Fatalf("loadExistingTranslations: %v", err)
+	}
+	if !existing["items_one"].Plural || !existing["items_other"].Plural {
+		t.Fatalf("expected Plural to be preserved when reloading an existing file, got %#v", existing)
+	}
+}
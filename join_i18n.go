@@ -3,9 +3,9 @@ package main
 import (
 	"crypto/rand"
 	"encoding/hex"
-	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -14,50 +14,67 @@ import (
 
 var joinI18nCmd = &cobra.Command{
 	Use:   "join_i18n [files...]",
-	Short: "Join multiple i18n JSON files into one",
-	Long:  "Merge multiple i18n JSON files into a single file, removing duplicates and prioritizing non-empty values",
+	Short: "Join multiple i18n files into one",
+	Long:  "Merge multiple i18n files into a single file, removing duplicates and prioritizing non-empty values. Each input's format (json, yaml, toml, po) is inferred from its extension, so files of different formats can be merged together. Pass --outdir to treat each input as a per-locale file and sync target locales against --source-lang instead.",
 	Args:  cobra.MinimumNArgs(1),
 	Run:   runJoinI18n,
 }
 
 var outputFile string
+var sourceLang string
+var outDir string
+var useSentinel bool
+var joinFormat string
+var joinFlat bool
 
 func init() {
 	joinI18nCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file path (defaults to random name if not specified)")
+	joinI18nCmd.Flags().StringVar(&sourceLang, "source-lang", "en", "Locale whose keys are canonical when --outdir is used")
+	joinI18nCmd.Flags().StringVar(&outDir, "outdir", "", "Write one synced file per locale (<outdir>/<lang>.<ext>) instead of a single merged file")
+	joinI18nCmd.Flags().BoolVar(&useSentinel, "sentinel", false, "Fill missing keys with TRANSLATE_ME:<source value> instead of an empty string")
+	joinI18nCmd.Flags().StringVar(&joinFormat, "format", "json", "Output format: json, yaml, toml, or po")
+	joinI18nCmd.Flags().BoolVar(&joinFlat, "flat", false, "Write dot-separated keys as a flat map instead of nesting them on output")
 }
 
 func runJoinI18n(cmd *cobra.Command, args []string) {
-	// Validate input files
 	for _, file := range args {
-		if !strings.HasSuffix(file, ".json") {
-			fmt.Fprintf(os.Stderr, "Error: %s is not a JSON file\n", file)
-			os.Exit(1)
-		}
 		if _, err := os.Stat(file); os.IsNotExist(err) {
 			fmt.Fprintf(os.Stderr, "Error: File %s does not exist\n", file)
 			os.Exit(1)
 		}
+		if _, err := marshallerForFile(file); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	outMarshaller, err := marshallerFor(joinFormat, !joinFlat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if outDir != "" {
+		runJoinI18nLocales(args, outMarshaller)
+		return
 	}
 
-	// Merge JSON files
-	merged, err := mergeJSONFiles(args)
+	merged, err := mergeFiles(args)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error merging files: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Determine output file name
+	ext := outMarshaller.Ext()
 	output := outputFile
 	if output == "" {
 		output = generateRandomFileName()
 	}
-	if !strings.HasSuffix(output, ".json") {
-		output += ".json"
+	if !strings.HasSuffix(output, "."+ext) {
+		output += "." + ext
 	}
 
-	// Write merged content to output file
-	err = writeJSONFile(output, merged)
-	if err != nil {
+	if err := writeFile(output, merged, outMarshaller); err != nil {
 		fmt.Fprintf(os.Stderr, "Error writing output file: %v\n", err)
 		os.Exit(1)
 	}
@@ -65,19 +82,157 @@ func runJoinI18n(cmd *cobra.Command, args []string) {
 	fmt.Printf("Successfully merged %d files into %s with %d keys\n", len(args), output, len(merged))
 }
 
-func mergeJSONFiles(files []string) (map[string]string, error) {
-	merged := make(map[string]string)
+// runJoinI18nLocales implements the --outdir mode: each input is a per-locale
+// file, the --source-lang file defines the canonical key set, and one synced
+// file per locale is written under outDir.
+func runJoinI18nLocales(files []string, outMarshaller Marshaller) {
+	locales, err := loadLocaleFiles(files)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading locale files: %v\n", err)
+		os.Exit(1)
+	}
+
+	sourceData, ok := locales[sourceLang]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: no input file found for source language %q\n", sourceLang)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(outDir, 0755); err != nil {
+		fmt.Fprintf(os.Stderr, "Error creating outdir %s: %v\n", outDir, err)
+		os.Exit(1)
+	}
+
+	canonicalKeys := make([]string, 0, len(sourceData))
+	for key := range sourceData {
+		canonicalKeys = append(canonicalKeys, key)
+	}
+	sort.Strings(canonicalKeys)
+
+	ext := outMarshaller.Ext()
+
+	for lang, data := range locales {
+		synced := make(map[string]string, len(canonicalKeys))
+		var stale []string
+
+		if lang == sourceLang {
+			// The source locale defines the canonical keys; it has nothing
+			// missing or stale by definition, so it's copied through as-is
+			// rather than run through the sentinel/empty-fill logic below
+			// (which would otherwise rewrite its own empty values, e.g.
+			// "farewell": "", into "TRANSLATE_ME:").
+			for key, value := range sourceData {
+				synced[key] = value
+			}
+		} else {
+			for _, key := range canonicalKeys {
+				if value, exists := data[key]; exists && value != "" {
+					synced[key] = value
+					continue
+				}
+				if useSentinel {
+					synced[key] = fmt.Sprintf("TRANSLATE_ME:%s", sourceData[key])
+				} else {
+					synced[key] = ""
+				}
+			}
+
+			for key := range data {
+				if _, inCanonical := sourceData[key]; !inCanonical {
+					stale = append(stale, key)
+				}
+			}
+		}
+
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			fmt.Fprintf(os.Stderr, "Warning: %s has %d stale key(s) not present in %s: %s\n", lang, len(stale), sourceLang, strings.Join(stale, ", "))
+
+			// Prefixing with "_stale." rather than embedding a serialized
+			// blob lets the active Marshaller nest these under a real
+			// "_stale" section on output, the same dot-path convention it
+			// already uses for ordinary nested translation keys.
+			for _, key := range stale {
+				synced["_stale."+key] = data[key]
+			}
+		}
+
+		outFile := filepath.Join(outDir, fmt.Sprintf("%s.%s", lang, ext))
+		if err := writeFile(outFile, synced, outMarshaller); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", outFile, err)
+			os.Exit(1)
+		}
+		fmt.Printf("Synced %s with %d keys (%d stale)\n", outFile, len(canonicalKeys), len(stale))
+	}
+}
+
+// loadLocaleFiles reads each input file and infers its locale from the
+// filename (e.g. "en.json" -> "en", "foo.en-US.json" -> "en-US"), erroring if
+// two inputs claim the same locale.
+func loadLocaleFiles(files []string) (map[string]map[string]string, error) {
+	locales := make(map[string]map[string]string, len(files))
+	claimedBy := make(map[string]string, len(files))
 
 	for _, file := range files {
-		content, err := os.ReadFile(file)
+		lang := inferLocale(file)
+
+		if existing, claimed := claimedBy[lang]; claimed {
+			return nil, fmt.Errorf("both %s and %s claim locale %q", existing, file, lang)
+		}
+		claimedBy[lang] = file
+
+		data, err := readTranslationFile(file)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read file %s: %v", file, err)
+			return nil, err
 		}
 
-		var data map[string]string
-		err = json.Unmarshal(content, &data)
+		locales[lang] = data
+	}
+
+	return locales, nil
+}
+
+// inferLocale derives a locale code from a translation file's name, stripping
+// its file extension (.json, .yaml, ...) and taking the final dot-separated segment so that
+// both "en.json" and "foo.en-US.json" resolve to their locale.
+func inferLocale(path string) string {
+	base := filepath.Base(path)
+	trimmed := strings.TrimSuffix(base, filepath.Ext(base))
+
+	if idx := strings.LastIndex(trimmed, "."); idx != -1 {
+		return trimmed[idx+1:]
+	}
+	return trimmed
+}
+
+// readTranslationFile reads a translation file using whatever Marshaller its
+// extension implies, so inputs of different formats can be merged together.
+func readTranslationFile(file string) (map[string]string, error) {
+	marshaller, err := marshallerForFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %v", file, err)
+	}
+
+	entries, err := marshaller.Unmarshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s in file %s: %v", marshaller.Ext(), file, err)
+	}
+
+	return entriesToMap(entries), nil
+}
+
+func mergeFiles(files []string) (map[string]string, error) {
+	merged := make(map[string]string)
+
+	for _, file := range files {
+		data, err := readTranslationFile(file)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse JSON in file %s: %v", file, err)
+			return nil, err
 		}
 
 		// Merge data, prioritizing non-empty values
@@ -99,27 +254,18 @@ func mergeJSONFiles(files []string) (map[string]string, error) {
 	return merged, nil
 }
 
-func writeJSONFile(filename string, data map[string]string) error {
-	// Sort keys for consistent output
-	keys := make([]string, 0, len(data))
-	for key := range data {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
-
-	// Create ordered map for JSON marshaling
-	orderedData := make(map[string]string)
-	for _, key := range keys {
-		orderedData[key] = data[key]
+func writeFile(filename string, data map[string]string, marshaller Marshaller) error {
+	entries := make([]TranslationEntry, 0, len(data))
+	for key, value := range data {
+		entries = append(entries, TranslationEntry{Key: key, Value: value})
 	}
 
-	jsonData, err := json.MarshalIndent(orderedData, "", "  ")
+	content, err := marshaller.Marshal(entries)
 	if err != nil {
-		return fmt.Errorf("failed to marshal JSON: %v", err)
+		return fmt.Errorf("failed to marshal %s: %v", marshaller.Ext(), err)
 	}
 
-	err = os.WriteFile(filename, jsonData, 0644)
-	if err != nil {
+	if err := os.WriteFile(filename, content, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %v", err)
 	}
 
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInferLocale(t *testing.T) {
+	cases := map[string]string{
+		"en.json":         "en",
+		"foo.en-US.json":  "en-US",
+		"fr.yaml":         "fr",
+		"dir/sub/de.json": "de",
+	}
+
+	for path, want := range cases {
+		if got := inferLocale(path); got != want {
+			t.Errorf("inferLocale(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func writeJSONFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+}
+
+func TestLoadLocaleFilesRejectsCollidingLocales(t *testing.T) {
+	dir := t.TempDir()
+	en := filepath.Join(dir, "en.json")
+	enDup := filepath.Join(dir, "foo.en.json")
+	writeJSONFile(t, en, `{"greeting": "Hello"}`)
+	writeJSONFile(t, enDup, `{"greeting": "Hi"}`)
+
+	_, err := loadLocaleFiles([]string{en, enDup})
+	if err == nil {
+		t.Fatal("expected an error when two files claim the same locale, got nil")
+	}
+}
+
+func TestLoadLocaleFiles(t *testing.T) {
+	dir := t.TempDir()
+	en := filepath.Join(dir, "en.json")
+	fr := filepath.Join(dir, "fr.json")
+	writeJSONFile(t, en, `{"greeting": "Hello"}`)
+	writeJSONFile(t, fr, `{"greeting": "Bonjour"}`)
+
+	locales, err := loadLocaleFiles([]string{en, fr})
+	if err != nil {
+		t.Fatalf("loadLocaleFiles: %v", err)
+	}
+	if locales["en"]["greeting"] != "Hello" || locales["fr"]["greeting"] != "Bonjour" {
+		t.Fatalf("unexpected locales: %#v", locales)
+	}
+}
+
+// TestRunJoinI18nLocalesSourceLangCopiedVerbatim guards against the sentinel
+// fill logic corrupting the source locale's own output file: the source
+// locale's empty values (e.g. an untranslated placeholder in the canonical
+// language itself) must pass through unchanged rather than becoming
+// "TRANSLATE_ME:".
+func TestRunJoinI18nLocalesSourceLangCopiedVerbatim(t *testing.T) {
+	dir := t.TempDir()
+	outDirPath := filepath.Join(dir, "out")
+	en := filepath.Join(dir, "en.json")
+	fr := filepath.Join(dir, "fr.json")
+	writeJSONFile(t, en, `{"greeting": "Hello", "farewell": ""}`)
+	writeJSONFile(t, fr, `{"greeting": "Bonjour"}`)
+
+	sourceLang = "en"
+	outDir = outDirPath
+	useSentinel = true
+	defer func() { sourceLang = "en"; outDir = ""; useSentinel = false }()
+
+	runJoinI18nLocales([]string{en, fr}, jsonMarshaller{})
+
+	enOut, err := readTranslationFile(filepath.Join(outDirPath, "en.json"))
+	if err != nil {
+		t.Fatalf("reading synced en.json: %v", err)
+	}
+	if enOut["farewell"] != "" {
+		t.Fatalf("expected source locale's own empty value to pass through unchanged, got %q", enOut["farewell"])
+	}
+	if enOut["greeting"] != "Hello" {
+		t.Fatalf("expected source locale's greeting to pass through unchanged, got %q", enOut["greeting"])
+	}
+
+	frOut, err := readTranslationFile(filepath.Join(outDirPath, "fr.json"))
+	if err != nil {
+		t.Fatalf("reading synced fr.json: %v", err)
+	}
+	if frOut["farewell"] != "TRANSLATE_ME:" {
+		t.Fatalf("expected fr's missing key to be sentinel-filled from en's empty value, got %q", frOut["farewell"])
+	}
+	if frOut["greeting"] != "Bonjour" {
+		t.Fatalf("expected fr's existing translation to be kept, got %q", frOut["greeting"])
+	}
+}
+
+// TestRunJoinI18nLocalesStaleKeys verifies that keys present in a target
+// locale but absent from the source locale's canonical set are moved under
+// the "_stale." prefix instead of being dropped.
+func TestRunJoinI18nLocalesStaleKeys(t *testing.T) {
+	dir := t.TempDir()
+	outDirPath := filepath.Join(dir, "out")
+	en := filepath.Join(dir, "en.json")
+	fr := filepath.Join(dir, "fr.json")
+	writeJSONFile(t, en, `{"greeting": "Hello"}`)
+	writeJSONFile(t, fr, `{"greeting": "Bonjour", "old_key": "Ancien"}`)
+
+	sourceLang = "en"
+	outDir = outDirPath
+	useSentinel = false
+	defer func() { sourceLang = "en"; outDir = ""; useSentinel = false }()
+
+	runJoinI18nLocales([]string{en, fr}, jsonMarshaller{})
+
+	frOut, err := readTranslationFile(filepath.Join(outDirPath, "fr.json"))
+	if err != nil {
+		t.Fatalf("reading synced fr.json: %v", err)
+	}
+	if frOut["_stale.old_key"] != "Ancien" {
+		t.Fatalf("expected stale key to be preserved under _stale. prefix, got %#v", frOut)
+	}
+	if _, exists := frOut["old_key"]; exists {
+		t.Fatalf("expected bare stale key to be removed, got %#v", frOut)
+	}
+}
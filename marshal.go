@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// TranslationEntry is the format-agnostic unit a Marshaller reads and
+// writes: a key/value pair plus the extraction metadata (namespace, source
+// file/line, plural status) that richer formats like PO attach as comments
+// or structure.
+type TranslationEntry struct {
+	Key       string
+	Value     string
+	Namespace string
+	File      string
+	Line      int
+	Plural    bool // true for both halves of a t(key, { count }) _one/_other pair
+}
+
+// Marshaller converts between a namespace's translation entries and one
+// on-disk file format. JSON, YAML and TOML round-trip a flat key/value map;
+// PO additionally renders reference comments and plural forms from the
+// _one/_other keys i18next itself uses on disk.
+type Marshaller interface {
+	Marshal(entries []TranslationEntry) ([]byte, error)
+	Unmarshal(data []byte) ([]TranslationEntry, error)
+	Ext() string
+}
+
+// marshallerFor resolves the --format flag ("json", "yaml", "toml", "po")
+// to its Marshaller. json is the default, matching the tool's original
+// behavior. nested controls whether Marshal emits dot-separated keys as
+// genuinely nested objects (i18next's own on-disk convention) or as a flat
+// map; it has no effect on poMarshaller, since gettext catalogs have no
+// notion of nesting.
+func marshallerFor(format string, nested bool) (Marshaller, error) {
+	switch format {
+	case "", "json":
+		return jsonMarshaller{nested: nested}, nil
+	case "yaml", "yml":
+		return yamlMarshaller{nested: nested}, nil
+	case "toml":
+		return tomlMarshaller{nested: nested}, nil
+	case "po":
+		return poMarshaller{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q (want json, yaml, toml, or po)", format)
+	}
+}
+
+// marshallerForFile infers a Marshaller from a file's extension, so
+// join_i18n can transcode whatever format each input implies.
+func marshallerForFile(path string) (Marshaller, error) {
+	switch {
+	case strings.HasSuffix(path, ".json"):
+		return jsonMarshaller{}, nil
+	case strings.HasSuffix(path, ".yaml"), strings.HasSuffix(path, ".yml"):
+		return yamlMarshaller{}, nil
+	case strings.HasSuffix(path, ".toml"):
+		return tomlMarshaller{}, nil
+	case strings.HasSuffix(path, ".po"), strings.HasSuffix(path, ".pot"):
+		return poMarshaller{}, nil
+	default:
+		return nil, fmt.Errorf("cannot infer format from file extension: %s", path)
+	}
+}
+
+func entriesToMap(entries []TranslationEntry) map[string]string {
+	m := make(map[string]string, len(entries))
+	for _, e := range entries {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+func sortedEntriesFromMap(m map[string]string) []TranslationEntry {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	entries := make([]TranslationEntry, 0, len(keys))
+	for _, k := range keys {
+		entries = append(entries, TranslationEntry{Key: k, Value: m[k]})
+	}
+	return entries
+}
+
+// nestMap expands a flat map of dot-separated keys (i18next's in-memory key
+// shape, e.g. "form.button.save") into the nested object structure it uses
+// on disk, e.g. {"form": {"button": {"save": ...}}}. It errors when a key
+// path collides with another key's value, e.g. both "foo" and "foo.bar"
+// present, since one would have to overwrite the other.
+func nestMap(flat map[string]string) (map[string]interface{}, error) {
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	root := make(map[string]interface{})
+	for _, key := range keys {
+		parts := strings.Split(key, ".")
+		node := root
+		for i, part := range parts {
+			if i == len(parts)-1 {
+				// Defensive: keys are processed in sorted order, so a
+				// shorter path like "foo" is always assigned before a
+				// longer one like "foo.bar" ever probes it, meaning the
+				// conflicting case is actually caught by the "conflicts
+				// with an existing value" branch below instead. Guarded
+				// here too in case that processing order ever changes.
+				if existing, ok := node[part]; ok {
+					if _, isMap := existing.(map[string]interface{}); isMap {
+						return nil, fmt.Errorf("key path collision: %q is both a value and a parent namespace", key)
+					}
+				}
+				node[part] = flat[key]
+				continue
+			}
+
+			child, ok := node[part]
+			if !ok {
+				child = make(map[string]interface{})
+				node[part] = child
+			}
+			next, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("key path collision: %q conflicts with an existing value at %q", key, strings.Join(parts[:i+1], "."))
+			}
+			node = next
+		}
+	}
+	return root, nil
+}
+
+// flattenMap collapses a (possibly already-flat) nested object back into
+// i18next's dot-separated key shape, the inverse of nestMap. Plain string
+// values at the top level pass through unchanged, so flat input files
+// continue to work with no special-casing.
+func flattenMap(nested map[string]interface{}) (map[string]string, error) {
+	flat := make(map[string]string)
+
+	var walk func(prefix string, m map[string]interface{}) error
+	walk = func(prefix string, m map[string]interface{}) error {
+		for key, value := range m {
+			full := key
+			if prefix != "" {
+				full = prefix + "." + key
+			}
+			switch v := value.(type) {
+			case map[string]interface{}:
+				if err := walk(full, v); err != nil {
+					return err
+				}
+			case string:
+				flat[full] = v
+			case nil:
+				flat[full] = ""
+			default:
+				return fmt.Errorf("unsupported value at key %q: expected a string or nested object", full)
+			}
+		}
+		return nil
+	}
+
+	if err := walk("", nested); err != nil {
+		return nil, err
+	}
+	return flat, nil
+}
+
+// decodeToStringMap normalizes a format-specific decode of map[string]any
+// (YAML/TOML decoders nest sub-tables as map[string]interface{} themselves,
+// but some decode nested tables under other map key types) into
+// map[string]interface{} so flattenMap can walk it uniformly.
+func decodeToStringMap(v interface{}) map[string]interface{} {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			result[k] = normalizeNested(val)
+		}
+		return result
+	case map[interface{}]interface{}:
+		result := make(map[string]interface{}, len(m))
+		for k, val := range m {
+			result[fmt.Sprintf("%v", k)] = normalizeNested(val)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func normalizeNested(v interface{}) interface{} {
+	if m := decodeToStringMap(v); m != nil {
+		return m
+	}
+	return v
+}
+
+type jsonMarshaller struct {
+	nested bool
+}
+
+func (jsonMarshaller) Ext() string { return "json" }
+
+func (m jsonMarshaller) Marshal(entries []TranslationEntry) ([]byte, error) {
+	flat := entriesToMap(entries)
+	if !m.nested {
+		return json.MarshalIndent(flat, "", "  ")
+	}
+	nested, err := nestMap(flat)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(nested, "", "  ")
+}
+
+func (jsonMarshaller) Unmarshal(data []byte) ([]TranslationEntry, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	flat, err := flattenMap(decodeToStringMap(raw))
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntriesFromMap(flat), nil
+}
+
+type yamlMarshaller struct {
+	nested bool
+}
+
+func (yamlMarshaller) Ext() string { return "yaml" }
+
+func (m yamlMarshaller) Marshal(entries []TranslationEntry) ([]byte, error) {
+	flat := entriesToMap(entries)
+	if !m.nested {
+		return yaml.Marshal(flat)
+	}
+	nested, err := nestMap(flat)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(nested)
+}
+
+func (yamlMarshaller) Unmarshal(data []byte) ([]TranslationEntry, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	flat, err := flattenMap(decodeToStringMap(raw))
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntriesFromMap(flat), nil
+}
+
+type tomlMarshaller struct {
+	nested bool
+}
+
+func (tomlMarshaller) Ext() string { return "toml" }
+
+func (m tomlMarshaller) Marshal(entries []TranslationEntry) ([]byte, error) {
+	flat := entriesToMap(entries)
+	var buf bytes.Buffer
+	if !m.nested {
+		if err := toml.NewEncoder(&buf).Encode(flat); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	nested, err := nestMap(flat)
+	if err != nil {
+		return nil, err
+	}
+	if err := toml.NewEncoder(&buf).Encode(nested); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (tomlMarshaller) Unmarshal(data []byte) ([]TranslationEntry, error) {
+	var raw map[string]interface{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	flat, err := flattenMap(decodeToStringMap(raw))
+	if err != nil {
+		return nil, err
+	}
+	return sortedEntriesFromMap(flat), nil
+}
+
+// poMarshaller renders/parses a gettext catalog. A pair of entries both
+// flagged Plural, one named "foo_one" and the other "foo_other", is combined
+// into a single msgid/msgid_plural entry; everything else is a plain entry.
+// Pairing is keyed off Plural rather than the _one/_other suffix alone, so
+// two unrelated entries that merely happen to share that naming convention
+// (but were never extracted from a t(key, { count }) call) aren't silently
+// merged into one stanza.
+type poMarshaller struct{}
+
+func (poMarshaller) Ext() string { return "po" }
+
+func (poMarshaller) Marshal(entries []TranslationEntry) ([]byte, error) {
+	byKey := make(map[string]TranslationEntry, len(entries))
+	keys := make([]string, 0, len(entries))
+	for _, e := range entries {
+		byKey[e.Key] = e
+		keys = append(keys, e.Key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	emitted := make(map[string]bool, len(entries))
+
+	for _, key := range keys {
+		if emitted[key] {
+			continue
+		}
+		entry := byKey[key]
+
+		if entry.Plural {
+			if base := strings.TrimSuffix(key, "_one"); base != key {
+				if other, ok := byKey[base+"_other"]; ok && other.Plural {
+					writePOPluralEntry(&buf, base, entry, other)
+					emitted[key] = true
+					emitted[base+"_other"] = true
+					continue
+				}
+			}
+			if base := strings.TrimSuffix(key, "_other"); base != key {
+				if one, ok := byKey[base+"_one"]; ok && one.Plural {
+					emitted[key] = true // emitted alongside its _one entry
+					continue
+				}
+			}
+		}
+
+		writePOEntry(&buf, entry)
+		emitted[key] = true
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writePOEntry(buf *bytes.Buffer, e TranslationEntry) {
+	if e.File != "" {
+		fmt.Fprintf(buf, "#: %s:%d\n", e.File, e.Line)
+	}
+	if e.Namespace != "" {
+		fmt.Fprintf(buf, "msgctxt %s\n", poQuote(e.Namespace))
+	}
+	fmt.Fprintf(buf, "msgid %s\n", poQuote(e.Key))
+	fmt.Fprintf(buf, "msgstr %s\n\n", poQuote(e.Value))
+}
+
+func writePOPluralEntry(buf *bytes.Buffer, base string, one, other TranslationEntry) {
+	if one.File != "" {
+		fmt.Fprintf(buf, "#: %s:%d\n", one.File, one.Line)
+	}
+	if one.Namespace != "" {
+		fmt.Fprintf(buf, "msgctxt %s\n", poQuote(one.Namespace))
+	}
+	fmt.Fprintf(buf, "msgid %s\n", poQuote(base))
+	fmt.Fprintf(buf, "msgid_plural %s\n", poQuote(base))
+	fmt.Fprintf(buf, "msgstr[0] %s\n", poQuote(one.Value))
+	fmt.Fprintf(buf, "msgstr[1] %s\n\n", poQuote(other.Value))
+}
+
+func poQuote(s string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "\"", "\\\"", "\n", "\\n")
+	return "\"" + replacer.Replace(s) + "\""
+}
+
+func (poMarshaller) Unmarshal(data []byte) ([]TranslationEntry, error) {
+	var entries []TranslationEntry
+	var ctx, msgid, msgstr, msgstr0, msgstr1, file string
+	var line int
+	var havePlural bool
+
+	flush := func() {
+		switch {
+		case msgid == "":
+			// nothing accumulated
+		case havePlural:
+			entries = append(entries,
+				TranslationEntry{Key: msgid + "_one", Value: msgstr0, Namespace: ctx, File: file, Line: line, Plural: true},
+				TranslationEntry{Key: msgid + "_other", Value: msgstr1, Namespace: ctx, File: file, Line: line, Plural: true},
+			)
+		default:
+			entries = append(entries, TranslationEntry{Key: msgid, Value: msgstr, Namespace: ctx, File: file, Line: line})
+		}
+		ctx, msgid, msgstr, msgstr0, msgstr1, file, line, havePlural = "", "", "", "", "", "", 0, false
+	}
+
+	for _, raw := range strings.Split(string(data), "\n") {
+		l := strings.TrimSpace(raw)
+
+		switch {
+		case l == "":
+			flush()
+		case strings.HasPrefix(l, "#:"):
+			ref := strings.TrimSpace(strings.TrimPrefix(l, "#:"))
+			if idx := strings.LastIndex(ref, ":"); idx != -1 {
+				file = ref[:idx]
+				line, _ = strconv.Atoi(ref[idx+1:])
+			}
+		case strings.HasPrefix(l, "msgctxt "):
+			ctx = poUnquote(strings.TrimPrefix(l, "msgctxt "))
+		case strings.HasPrefix(l, "msgid_plural "):
+			havePlural = true
+		case strings.HasPrefix(l, "msgid "):
+			msgid = poUnquote(strings.TrimPrefix(l, "msgid "))
+		case strings.HasPrefix(l, "msgstr[0] "):
+			msgstr0 = poUnquote(strings.TrimPrefix(l, "msgstr[0] "))
+		case strings.HasPrefix(l, "msgstr[1] "):
+			msgstr1 = poUnquote(strings.TrimPrefix(l, "msgstr[1] "))
+		case strings.HasPrefix(l, "msgstr "):
+			msgstr = poUnquote(strings.TrimPrefix(l, "msgstr "))
+		}
+	}
+	flush()
+
+	return entries, nil
+}
+
+func poUnquote(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	replacer := strings.NewReplacer("\\n", "\n", "\\\"", "\"", "\\\\", "\\")
+	return replacer.Replace(s)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func entriesMap(t *testing.T, entries []TranslationEntry) map[string]string {
+	t.Helper()
+	m := make(map[string]string, len(entries))
+	for _, e := range entries {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+func TestMarshallerRoundTrip(t *testing.T) {
+	entries := []TranslationEntry{
+		{Key: "greeting", Value: "Hello", Namespace: "common"},
+		{Key: "farewell", Value: "", Namespace: "common"},
+	}
+
+	for _, m := range []Marshaller{jsonMarshaller{}, yamlMarshaller{}, tomlMarshaller{}} {
+		t.Run(m.Ext(), func(t *testing.T) {
+			data, err := m.Marshal(entries)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+
+			got, err := m.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			gotMap := entriesMap(t, got)
+			want := entriesMap(t, entries)
+			if len(gotMap) != len(want) {
+				t.Fatalf("round-tripped %d entries, want %d (got %v)", len(gotMap), len(want), gotMap)
+			}
+			for k, v := range want {
+				if gotMap[k] != v {
+					t.Errorf("key %q = %q, want %q", k, gotMap[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestPOMarshallerPluralRoundTrip(t *testing.T) {
+	entries := []TranslationEntry{
+		{Key: "items_one", Value: "one item", Namespace: "common", File: "Sample.tsx", Line: 5, Plural: true},
+		{Key: "items_other", Value: "many items", Namespace: "common", File: "Sample.tsx", Line: 5, Plural: true},
+		{Key: "greeting", Value: "Hello", Namespace: "common", File: "Sample.tsx", Line: 7},
+	}
+
+	var m poMarshaller
+	data, err := m.Marshal(entries)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	poText := string(data)
+	if occurrences := countOccurrences(poText, "msgid \"items\""); occurrences != 1 {
+		t.Fatalf("expected exactly one msgid %q stanza, found %d in:\n%s", "items", occurrences, poText)
+	}
+
+	got, err := m.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	gotMap := entriesMap(t, got)
+
+	want := map[string]string{
+		"items_one":   "one item",
+		"items_other": "many items",
+		"greeting":    "Hello",
+	}
+	if len(gotMap) != len(want) {
+		t.Fatalf("round-tripped %d entries, want %d (got %v)", len(gotMap), len(want), gotMap)
+	}
+	for k, v := range want {
+		if gotMap[k] != v {
+			t.Errorf("key %q = %q, want %q", k, gotMap[k], v)
+		}
+	}
+}
+
+// TestPOMarshallerDoesNotPairUnrelatedOneOtherKeys guards against pairing
+// keys purely by their _one/_other suffix: two keys that merely share that
+// naming convention, but were never extracted from a t(key, { count })
+// call (Plural: false), must stay as separate msgid stanzas.
+func TestPOMarshallerDoesNotPairUnrelatedOneOtherKeys(t *testing.T) {
+	entries := []TranslationEntry{
+		{Key: "rating_one", Value: "first rating", Namespace: "common"},
+		{Key: "rating_other", Value: "other rating", Namespace: "common"},
+	}
+
+	var m poMarshaller
+	data, err := m.Marshal(entries)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	poText := string(data)
+	if strings.Contains(poText, "msgid_plural") {
+		t.Fatalf("expected no msgid_plural stanza for non-Plural entries, got:\n%s", poText)
+	}
+	if occurrences := countOccurrences(poText, "msgid \"rating_one\""); occurrences != 1 {
+		t.Fatalf("expected rating_one to be written as its own plain msgid, found %d occurrences in:\n%s", occurrences, poText)
+	}
+	if occurrences := countOccurrences(poText, "msgid \"rating_other\""); occurrences != 1 {
+		t.Fatalf("expected rating_other to be written as its own plain msgid, found %d occurrences in:\n%s", occurrences, poText)
+	}
+}
+
+func countOccurrences(s, substr string) int {
+	count := 0
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			count++
+		}
+	}
+	return count
+}
+
+func TestNestedMarshalRoundTripThroughJSON(t *testing.T) {
+	entries := []TranslationEntry{
+		{Key: "form.button.save", Value: "Save"},
+		{Key: "form.button.cancel", Value: "Cancel"},
+		{Key: "greeting", Value: "Hello"},
+	}
+
+	m := jsonMarshaller{nested: true}
+	data, err := m.Marshal(entries)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	got, err := m.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	gotKeys := make([]string, 0, len(got))
+	for _, e := range got {
+		gotKeys = append(gotKeys, e.Key)
+	}
+	sort.Strings(gotKeys)
+
+	want := []string{"form.button.cancel", "form.button.save", "greeting"}
+	if len(gotKeys) != len(want) {
+		t.Fatalf("got keys %v, want %v", gotKeys, want)
+	}
+	for i, k := range want {
+		if gotKeys[i] != k {
+			t.Fatalf("got keys %v, want %v", gotKeys, want)
+		}
+	}
+}
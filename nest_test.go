@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestNestMapFlattenMapRoundTrip(t *testing.T) {
+	flat := map[string]string{
+		"form.button.save":   "Save",
+		"form.button.cancel": "Cancel",
+		"greeting":           "Hello",
+	}
+
+	nested, err := nestMap(flat)
+	if err != nil {
+		t.Fatalf("nestMap: %v", err)
+	}
+
+	form, ok := nested["form"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested[\"form\"] to be a nested object, got %#v", nested["form"])
+	}
+	button, ok := form["button"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested[\"form\"][\"button\"] to be a nested object, got %#v", form["button"])
+	}
+	if button["save"] != "Save" || button["cancel"] != "Cancel" {
+		t.Fatalf("unexpected nested button object: %#v", button)
+	}
+	if nested["greeting"] != "Hello" {
+		t.Fatalf("expected top-level greeting to pass through unnested, got %#v", nested["greeting"])
+	}
+
+	roundTripped, err := flattenMap(nested)
+	if err != nil {
+		t.Fatalf("flattenMap: %v", err)
+	}
+	if len(roundTripped) != len(flat) {
+		t.Fatalf("flattenMap produced %d keys, want %d (got %v)", len(roundTripped), len(flat), roundTripped)
+	}
+	for k, v := range flat {
+		if roundTripped[k] != v {
+			t.Errorf("key %q = %q, want %q", k, roundTripped[k], v)
+		}
+	}
+}
+
+// nestMap always sorts keys before walking them, so "foo" and "foo.bar"
+// collide in a single, deterministic way regardless of the input map's
+// (unordered) iteration order: "foo" is assigned as a leaf first, then
+// "foo.bar" fails to descend into it. There is no way to drive a Go map
+// literal through the opposite order, so this is the only collision shape
+// actually reachable through the public nestMap API.
+func TestNestMapCollisionBetweenValueAndNestedPath(t *testing.T) {
+	_, err := nestMap(map[string]string{
+		"foo":     "x",
+		"foo.bar": "y",
+	})
+	if err == nil {
+		t.Fatal("expected an error when a key path collides with an existing value, got nil")
+	}
+}
+
+func TestFlattenMapPassesThroughAlreadyFlatInput(t *testing.T) {
+	nested := map[string]interface{}{
+		"greeting": "Hello",
+		"farewell": "Bye",
+	}
+
+	flat, err := flattenMap(nested)
+	if err != nil {
+		t.Fatalf("flattenMap: %v", err)
+	}
+	if flat["greeting"] != "Hello" || flat["farewell"] != "Bye" {
+		t.Fatalf("unexpected flatten result: %v", flat)
+	}
+}
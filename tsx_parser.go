@@ -0,0 +1,468 @@
+package main
+
+import "strings"
+
+// tsxEventKind identifies what kind of lexical construct a tsxEvent describes.
+type tsxEventKind int
+
+const (
+	eventCall tsxEventKind = iota
+	eventJSXElement
+)
+
+// tsxArg is one call argument or JSX attribute value discovered by the
+// tokenizer. IsLiteral is true only when the value is a plain string or a
+// template literal with no ${...} interpolation, i.e. something we can
+// resolve to a concrete string at extraction time, or a JSX attribute given
+// without a value, which reads as the literal "true".
+type tsxArg struct {
+	Raw       string
+	Literal   string
+	IsLiteral bool
+	Dynamic   bool
+}
+
+// tsxEvent is one call expression or JSX element discovered while tokenizing
+// a file.
+type tsxEvent struct {
+	Kind    tsxEventKind
+	Name    string
+	Args    []tsxArg
+	Attrs   map[string]tsxArg
+	Options string // raw source of a trailing `{ ... }` options object, if any
+	Offset  int
+	Line    int
+}
+
+// hasCountOption reports whether a call's options object declares a "count"
+// property, the i18next convention for pluralized calls.
+func (e tsxEvent) hasCountOption() bool {
+	opts := e.Options
+	for from := 0; ; {
+		idx := strings.Index(opts[from:], "count")
+		if idx == -1 {
+			return false
+		}
+		start, end := from+idx, from+idx+len("count")
+		before, after := skipSpaceBackward(opts, start), skipSpace(opts, end)
+		if before > 0 && (opts[before-1] == '{' || opts[before-1] == ',') &&
+			after < len(opts) && (opts[after] == ':' || opts[after] == ',' || opts[after] == '}') {
+			return true
+		}
+		from = end
+	}
+}
+
+// tokenizeTSX walks TSX source with a small state machine over comments,
+// string/template literals and JSX tags, emitting one event per call
+// expression and per JSX opening tag it finds. It is deliberately not a full
+// TypeScript parser: it understands just enough syntax to locate i18next
+// call/JSX idioms without tripping over code that lives inside strings or
+// comments.
+func tokenizeTSX(content string) []tsxEvent {
+	var events []tsxEvent
+	n := len(content)
+	i := 0
+
+	for i < n {
+		c := content[i]
+
+		switch {
+		case c == '/' && i+1 < n && content[i+1] == '/':
+			for i < n && content[i] != '\n' {
+				i++
+			}
+
+		case c == '/' && i+1 < n && content[i+1] == '*':
+			if end := strings.Index(content[i+2:], "*/"); end == -1 {
+				i = n
+			} else {
+				i = i + 2 + end + 2
+			}
+
+		case c == '\'' || c == '"':
+			i = skipStringLiteral(content, i)
+
+		case c == '`':
+			i = skipTemplateLiteral(content, i)
+
+		case c == '<' && isJSXStart(content, i):
+			event, next := parseJSXOpenTag(content, i)
+			if event != nil {
+				events = append(events, *event)
+			}
+			i = next
+
+		case isIdentStart(c):
+			start := i
+			i = readIdentifier(content, i)
+			name := content[start:i]
+
+			j := skipSpace(content, i)
+			if j < n && content[j] == '(' {
+				// Only the call's own argument list is parsed here, to
+				// capture its positional args and options; the tokenizer
+				// then continues scanning character-by-character from right
+				// after the '(' so strings, nested calls and JSX inside the
+				// arguments are still discovered as their own events (e.g.
+				// a `return (<Trans ...>...)` or `useEffect(() => t(...))`).
+				args, options, _ := parseCallArgs(content, j+1)
+				events = append(events, tsxEvent{
+					Kind:    eventCall,
+					Name:    name,
+					Args:    args,
+					Options: options,
+					Offset:  start,
+					Line:    lineAt(content, start),
+				})
+				i = j + 1
+				continue
+			}
+
+		default:
+			i++
+		}
+	}
+
+	return events
+}
+
+func lineAt(content string, offset int) int {
+	if offset > len(content) {
+		offset = len(content)
+	}
+	return strings.Count(content[:offset], "\n") + 1
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9')
+}
+
+func readIdentifier(content string, i int) int {
+	n := len(content)
+	for i < n && isIdentPart(content[i]) {
+		i++
+	}
+	return i
+}
+
+func skipSpace(content string, i int) int {
+	n := len(content)
+	for i < n && (content[i] == ' ' || content[i] == '\t' || content[i] == '\n' || content[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipStringLiteral advances past a '...' or "..." literal starting at i,
+// honoring backslash escapes.
+func skipStringLiteral(content string, i int) int {
+	quote := content[i]
+	n := len(content)
+	i++
+	for i < n {
+		if content[i] == '\\' {
+			i += 2
+			continue
+		}
+		if content[i] == quote {
+			return i + 1
+		}
+		i++
+	}
+	return n
+}
+
+// skipTemplateLiteral advances past a `...` literal starting at i, recursing
+// into ${...} interpolations so nested strings/braces don't confuse the
+// backtick search.
+func skipTemplateLiteral(content string, i int) int {
+	n := len(content)
+	i++
+	for i < n {
+		switch content[i] {
+		case '\\':
+			i += 2
+		case '`':
+			return i + 1
+		case '$':
+			if i+1 < n && content[i+1] == '{' {
+				i = skipBalanced(content, i+1, '{', '}')
+			} else {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+	return n
+}
+
+// skipBalanced advances from an opening bracket at i to the index just past
+// its matching close, skipping over nested brackets, strings, templates and
+// comments along the way.
+func skipBalanced(content string, i int, open, close byte) int {
+	n := len(content)
+	depth := 0
+
+	for i < n {
+		c := content[i]
+		switch {
+		case c == open:
+			depth++
+			i++
+		case c == close:
+			depth--
+			i++
+			if depth == 0 {
+				return i
+			}
+		case c == '\'' || c == '"':
+			i = skipStringLiteral(content, i)
+		case c == '`':
+			i = skipTemplateLiteral(content, i)
+		case c == '/' && i+1 < n && content[i+1] == '/':
+			for i < n && content[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < n && content[i+1] == '*':
+			if end := strings.Index(content[i+2:], "*/"); end == -1 {
+				i = n
+			} else {
+				i = i + 2 + end + 2
+			}
+		default:
+			i++
+		}
+	}
+	return n
+}
+
+// parseCallArgs parses the comma-separated argument list starting right
+// after a call's opening '(' at i. Positional string/template/array
+// arguments are reported as tsxArgs; a top-level trailing options object
+// (the `{ count }` in `t(key, { count })`, or `{ keyPrefix: 'x' }` in
+// useTranslation's second argument) is reported separately via its raw
+// source text rather than as a positional arg, since it never carries a
+// translation key itself.
+func parseCallArgs(content string, i int) (args []tsxArg, options string, end int) {
+	n := len(content)
+	depth := 1
+	argStart := i
+
+	flush := func(stop int) {
+		if stop < argStart {
+			return
+		}
+		raw := strings.TrimSpace(content[argStart:stop])
+		if raw != "" {
+			args = append(args, parseArg(raw))
+		}
+	}
+
+	for i < n && depth > 0 {
+		c := content[i]
+
+		switch c {
+		case '\'', '"':
+			i = skipStringLiteral(content, i)
+		case '`':
+			i = skipTemplateLiteral(content, i)
+		case '{':
+			if depth == 1 {
+				objEnd := skipBalanced(content, i, '{', '}')
+				options = content[i:objEnd]
+				i = objEnd
+				argStart = i
+			} else {
+				i++
+			}
+		case '(', '[':
+			depth++
+			i++
+		case ')', ']':
+			depth--
+			i++
+			if depth == 0 {
+				flush(i - 1)
+				return args, options, i
+			}
+		case ',':
+			if depth == 1 {
+				flush(i)
+				i++
+				argStart = i
+			} else {
+				i++
+			}
+		default:
+			i++
+		}
+	}
+
+	return args, options, i
+}
+
+// parseArg classifies a single trimmed argument: a plain string or
+// interpolation-free template literal resolves to a literal value; an array
+// literal is kept raw (callers that expect one, like useTranslation's
+// namespace list, parse it further); anything else - concatenation,
+// identifiers, interpolated templates - is flagged Dynamic since its value
+// can't be known without evaluating the code. Matching quote characters at
+// both ends isn't enough to call something a literal: `'a' + 'b'` also
+// starts and ends with `'`, so the literal's own end must land exactly at
+// the end of raw, not just share its closing character.
+func parseArg(raw string) tsxArg {
+	if len(raw) >= 2 && (raw[0] == '\'' || raw[0] == '"') {
+		if end := skipStringLiteral(raw, 0); end == len(raw) {
+			return tsxArg{Raw: raw, Literal: unescapeString(raw[1 : len(raw)-1]), IsLiteral: true}
+		}
+		return tsxArg{Raw: raw, Dynamic: true}
+	}
+
+	if len(raw) >= 2 && raw[0] == '`' {
+		if end := skipTemplateLiteral(raw, 0); end == len(raw) {
+			inner := raw[1 : len(raw)-1]
+			if !strings.Contains(inner, "${") {
+				return tsxArg{Raw: raw, Literal: inner, IsLiteral: true}
+			}
+		}
+		return tsxArg{Raw: raw, Dynamic: true}
+	}
+
+	if len(raw) >= 2 && raw[0] == '[' && raw[len(raw)-1] == ']' {
+		return tsxArg{Raw: raw}
+	}
+
+	return tsxArg{Raw: raw, Dynamic: true}
+}
+
+func unescapeString(s string) string {
+	replacer := strings.NewReplacer("\\\\", "\\", "\\'", "'", "\\\"", "\"")
+	return replacer.Replace(s)
+}
+
+// stringArrayLiteral parses a `['common', 'auth']`-style literal into its
+// string elements. It reports ok=false for anything else (a single
+// namespace string, a spread, a variable).
+func stringArrayLiteral(raw string) (elems []string, ok bool) {
+	raw = strings.TrimSpace(raw)
+	if len(raw) < 2 || raw[0] != '[' || raw[len(raw)-1] != ']' {
+		return nil, false
+	}
+
+	inner := raw[1 : len(raw)-1]
+
+	for _, part := range splitTopLevel(inner, ',') {
+		arg := parseArg(strings.TrimSpace(part))
+		if !arg.IsLiteral {
+			return nil, false
+		}
+		elems = append(elems, arg.Literal)
+	}
+	return elems, len(elems) > 0
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside nested
+// brackets, strings or templates.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	n := len(s)
+
+	for i := 0; i < n; {
+		switch s[i] {
+		case '\'', '"':
+			i = skipStringLiteral(s, i)
+			continue
+		case '`':
+			i = skipTemplateLiteral(s, i)
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		default:
+			if s[i] == sep && depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+		i++
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+func isJSXStart(content string, i int) bool {
+	if i+1 >= len(content) {
+		return false
+	}
+	c := content[i+1]
+	return c >= 'A' && c <= 'Z'
+}
+
+// parseJSXOpenTag parses a JSX opening tag - <Name attr="x" attr2={expr} />
+// or <Name attr="x"> - starting at the '<' at i, returning the element
+// event and the index just past the tag's closing '>'.
+func parseJSXOpenTag(content string, i int) (*tsxEvent, int) {
+	n := len(content)
+	start := i
+	i++
+	nameStart := i
+	i = readIdentifier(content, i)
+	name := content[nameStart:i]
+
+	attrs := make(map[string]tsxArg)
+
+	for i < n {
+		i = skipSpace(content, i)
+		if i >= n {
+			break
+		}
+		if content[i] == '/' && i+1 < n && content[i+1] == '>' {
+			i += 2
+			break
+		}
+		if content[i] == '>' {
+			i++
+			break
+		}
+		if !isIdentStart(content[i]) {
+			// Not actually a JSX tag (e.g. a `<` comparison); bail out
+			// having consumed only the `<`.
+			return nil, start + 1
+		}
+
+		attrNameStart := i
+		for i < n && (isIdentPart(content[i]) || content[i] == '-') {
+			i++
+		}
+		attrName := content[attrNameStart:i]
+
+		i = skipSpace(content, i)
+		if i < n && content[i] == '=' {
+			i++
+			i = skipSpace(content, i)
+			switch {
+			case i < n && (content[i] == '\'' || content[i] == '"'):
+				valStart := i
+				i = skipStringLiteral(content, i)
+				attrs[attrName] = parseArg(content[valStart:i])
+			case i < n && content[i] == '{':
+				valStart := i
+				i = skipBalanced(content, i, '{', '}')
+				attrs[attrName] = parseArg(content[valStart:i])
+			}
+		} else {
+			attrs[attrName] = tsxArg{Raw: attrName, Literal: "true", IsLiteral: true}
+		}
+	}
+
+	return &tsxEvent{Kind: eventJSXElement, Name: name, Attrs: attrs, Offset: start, Line: lineAt(content, start)}, i
+}
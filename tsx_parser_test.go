@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestParseArgLiteral(t *testing.T) {
+	cases := []struct {
+		name    string
+		raw     string
+		literal string
+		dynamic bool
+	}{
+		{"single-quoted", "'submit'", "submit", false},
+		{"double-quoted", `"submit"`, "submit", false},
+		{"template-no-interpolation", "`submit`", "submit", false},
+		{"template-with-interpolation", "`submit.${id}`", "", true},
+		{"concatenation", "'a' + 'b'", "", true},
+		{"concatenation-mixed-quotes", `'a' + "b"`, "", true},
+		{"identifier", "someVar", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			arg := parseArg(tc.raw)
+			if arg.Dynamic != tc.dynamic {
+				t.Fatalf("parseArg(%q).Dynamic = %v, want %v", tc.raw, arg.Dynamic, tc.dynamic)
+			}
+			if !tc.dynamic && arg.Literal != tc.literal {
+				t.Fatalf("parseArg(%q).Literal = %q, want %q", tc.raw, arg.Literal, tc.literal)
+			}
+			if !tc.dynamic && !arg.IsLiteral {
+				t.Fatalf("parseArg(%q).IsLiteral = false, want true", tc.raw)
+			}
+		})
+	}
+}
+
+func TestTokenizeTSXFindsNestedCallsInsideJSX(t *testing.T) {
+	src := `
+function Sample() {
+  return (
+    <Trans i18nKey="greeting">
+      {t('inline.key')}
+    </Trans>
+  );
+}
+`
+	events := tokenizeTSX(src)
+
+	var sawTrans, sawCall bool
+	for _, e := range events {
+		if e.Kind == eventJSXElement && e.Name == "Trans" {
+			sawTrans = true
+		}
+		if e.Kind == eventCall && e.Name == "t" {
+			sawCall = true
+		}
+	}
+	if !sawTrans {
+		t.Fatal("expected a Trans JSX element event, found none")
+	}
+	if !sawCall {
+		t.Fatal("expected a t(...) call event nested inside the JSX, found none")
+	}
+}
+
+func TestTokenizeTSXCountOption(t *testing.T) {
+	events := tokenizeTSX(`t('items', { count })`)
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	if !events[0].hasCountOption() {
+		t.Fatal("expected hasCountOption() to be true for { count }")
+	}
+}